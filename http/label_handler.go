@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/label"
+	"go.uber.org/zap"
+)
+
+// LabelHandler handles the bulk label-mapping and label-history endpoints
+// shared across every labelable resource type.
+type LabelHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	LabelService influxdb.LabelService
+}
+
+// NewLabelHandler constructs a LabelHandler and registers its routes.
+func NewLabelHandler(log *zap.Logger, labelService influxdb.LabelService, httpErrorHandler influxdb.HTTPErrorHandler) *LabelHandler {
+	h := &LabelHandler{
+		Router:           NewRouter(httpErrorHandler),
+		HTTPErrorHandler: httpErrorHandler,
+		log:              log,
+		LabelService:     labelService,
+	}
+
+	h.HandlerFunc("POST", "/api/v2/:resource/:id/labels", h.handleAddResourceLabels)
+	h.HandlerFunc("PUT", "/api/v2/:resource/:id/labels", h.handleReplaceResourceLabels)
+	h.HandlerFunc("DELETE", "/api/v2/:resource/:id/labels", h.handleClearResourceLabels)
+	h.HandlerFunc("GET", "/api/v2/:resource/:id/labels/history", h.handleGetLabelHistory)
+
+	return h
+}
+
+type labelIDsRequest struct {
+	LabelIDs []influxdb.ID `json:"labelIDs"`
+}
+
+func decodeLabelIDsRequest(r *http.Request) (*labelIDsRequest, error) {
+	req := &labelIDsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return req, nil
+}
+
+func resourceRefFromRequest(r *http.Request) (influxdb.ID, influxdb.ResourceType, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(params.ByName("id")); err != nil {
+		return 0, "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	resourceType := influxdb.ResourceType(params.ByName("resource"))
+	return id, resourceType, nil
+}
+
+// handleAddResourceLabels handles POST /api/v2/{resource}/{id}/labels.
+func (h *LabelHandler) handleAddResourceLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, resourceType, err := resourceRefFromRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLabelIDsRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.LabelService.AddResourceLabels(ctx, id, resourceType, req.LabelIDs); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.respondWithResourceLabels(w, r, id, resourceType)
+}
+
+// handleReplaceResourceLabels handles PUT /api/v2/{resource}/{id}/labels.
+func (h *LabelHandler) handleReplaceResourceLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, resourceType, err := resourceRefFromRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLabelIDsRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.LabelService.ReplaceResourceLabels(ctx, id, resourceType, req.LabelIDs); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.respondWithResourceLabels(w, r, id, resourceType)
+}
+
+// handleClearResourceLabels handles DELETE /api/v2/{resource}/{id}/labels.
+func (h *LabelHandler) handleClearResourceLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, resourceType, err := resourceRefFromRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.LabelService.ClearResourceLabels(ctx, id, resourceType); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LabelHandler) respondWithResourceLabels(w http.ResponseWriter, r *http.Request, id influxdb.ID, resourceType influxdb.ResourceType) {
+	ctx := r.Context()
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id, ResourceType: resourceType})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, map[string]interface{}{"labels": labels}); err != nil {
+		h.log.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// labelHistoryService is implemented by label.Service; asserted against
+// rather than added to influxdb.LabelService so label history stays an
+// extension of the core interface rather than a required method every
+// implementation (including test fakes) must provide.
+type labelHistoryService interface {
+	FindLabelHistory(ctx context.Context, filter influxdb.LabelHistoryFilter) ([]label.LabelEvent, error)
+}
+
+// handleGetLabelHistory handles GET /api/v2/{resource}/{id}/labels/history.
+func (h *LabelHandler) handleGetLabelHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, resourceType, err := resourceRefFromRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	svc, ok := h.LabelService.(labelHistoryService)
+	if !ok {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.ENotImplemented,
+			Msg:  "label history is not supported by this label service",
+		}, w)
+		return
+	}
+
+	events, err := svc.FindLabelHistory(ctx, influxdb.LabelHistoryFilter{ResourceID: id, ResourceType: resourceType})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, map[string]interface{}{"events": events}); err != nil {
+		h.log.Error("failed to encode response", zap.Error(err))
+	}
+}
@@ -0,0 +1,183 @@
+package label
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// labelEventBucket stores LabelEvent entries keyed by
+// resourceID || timestamp so a resource's history sorts naturally within
+// its key prefix.
+var labelEventBucket = []byte("labelevents")
+
+// labelEventKey encodes the (resourceID, timestamp) pair used to key
+// label audit entries.
+func labelEventKey(resourceID influxdb.ID, t time.Time) ([]byte, error) {
+	encodedID, err := resourceID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(encodedID)+8)
+	copy(key, encodedID)
+	binary.BigEndian.PutUint64(key[len(encodedID):], uint64(t.UnixNano()))
+	return key, nil
+}
+
+// LabelEventType enumerates the kinds of label mutation the audit log
+// records.
+type LabelEventType string
+
+const (
+	LabelEventMappingCreated LabelEventType = "mapping_created"
+	LabelEventMappingDeleted LabelEventType = "mapping_deleted"
+	LabelEventLabelUpdated   LabelEventType = "label_updated"
+)
+
+// LabelEvent is an immutable record of a single label mutation against a
+// resource, letting operators see who tagged what and when.
+type LabelEvent struct {
+	ResourceID   influxdb.ID           `json:"resourceID"`
+	ResourceType influxdb.ResourceType `json:"resourceType"`
+	LabelID      influxdb.ID           `json:"labelID"`
+	LabelName    string                `json:"labelName"`
+	Type         LabelEventType        `json:"type"`
+	Actor        string                `json:"actor"`
+	Time         time.Time             `json:"time"`
+}
+
+// recordLabelEvent appends evt to the audit log against an already-open
+// transaction, stamping the actor from ctx's authorizer.
+func recordLabelEvent(ctx context.Context, tx kv.Tx, store *Store, evt LabelEvent) error {
+	evt.Actor = actorFromContext(ctx)
+	return store.PutLabelEvent(ctx, tx, evt)
+}
+
+// actorFromContext extracts a human-readable identity for the audit trail
+// from the authorizer on ctx, falling back to "unknown" so an entry is
+// never silently dropped for lack of an authorizer.
+func actorFromContext(ctx context.Context) string {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil || a == nil {
+		return "unknown"
+	}
+	return a.Identifier().String()
+}
+
+// PutLabelEvent appends evt to the audit log, keyed by (resourceID,
+// timestamp) so a resource's history can be scanned in time order.
+func (s *Store) PutLabelEvent(ctx context.Context, tx kv.Tx, evt LabelEvent) error {
+	b, err := tx.Bucket(labelEventBucket)
+	if err != nil {
+		return kv.UnexpectedIndexError(err)
+	}
+
+	key, err := labelEventKey(evt.ResourceID, evt.Time)
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(key, val)
+}
+
+// FindLabelEvents returns the audit entries matching filter, newest
+// first.
+func (s *Store) FindLabelEvents(ctx context.Context, tx kv.Tx, filter influxdb.LabelHistoryFilter) ([]LabelEvent, error) {
+	b, err := tx.Bucket(labelEventBucket)
+	if err != nil {
+		return nil, kv.UnexpectedIndexError(err)
+	}
+
+	prefix, err := filter.ResourceID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.ForwardCursor(prefix, kv.WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	var events []LabelEvent
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var evt LabelEvent
+		if err := json.Unmarshal(v, &evt); err != nil {
+			return nil, err
+		}
+		if filter.ResourceType != "" && evt.ResourceType != filter.ResourceType {
+			continue
+		}
+		events = append(events, evt)
+	}
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	return events, cur.Err()
+}
+
+// FindMappedResources returns every mapping currently pointing at
+// labelID, used to fan an UpdateLabel audit event out to each resource
+// the label is attached to.
+func (s *Store) FindMappedResources(ctx context.Context, tx kv.Tx, labelID influxdb.ID) ([]*influxdb.LabelMapping, error) {
+	return s.findLabelMappings(ctx, tx, labelID)
+}
+
+// findLabelMappings returns every mapping currently pointing at labelID,
+// via a prefix scan on the mapping index (see labelMappingIndexKey).
+func (s *Store) findLabelMappings(ctx context.Context, tx kv.Tx, labelID influxdb.ID) ([]*influxdb.LabelMapping, error) {
+	b, err := tx.Bucket(labelMappingIndexBucket)
+	if err != nil {
+		return nil, kv.UnexpectedIndexError(err)
+	}
+
+	prefix, err := labelID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.ForwardCursor(prefix, kv.WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	var mappings []*influxdb.LabelMapping
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var m influxdb.LabelMapping
+		if err := json.Unmarshal(v, &m); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, &m)
+	}
+
+	return mappings, cur.Err()
+}
+
+// FindLabelHistory returns the audit trail of label mutations matching
+// filter, newest first.
+func (s *Service) FindLabelHistory(ctx context.Context, filter influxdb.LabelHistoryFilter) ([]LabelEvent, error) {
+	var events []LabelEvent
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		evts, err := s.store.FindLabelEvents(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		events = evts
+		return nil
+	})
+	return events, err
+}
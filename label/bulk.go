@@ -0,0 +1,180 @@
+package label
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// AddResourceLabels applies labelIDs to the resource in a single KV
+// transaction. Labels already mapped to the resource are skipped rather
+// than returning influxdb.ErrLabelExistsOnResource, and a label that
+// fails to apply does not stop the rest of the batch; failures are
+// aggregated into an influxdb.MultiError.
+func (s *Service) AddResourceLabels(ctx context.Context, resourceID influxdb.ID, resourceType influxdb.ResourceType, labelIDs []influxdb.ID) error {
+	var errs influxdb.MultiError
+
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		for _, id := range labelIDs {
+			m := &influxdb.LabelMapping{LabelID: id, ResourceID: resourceID, ResourceType: resourceType}
+
+			has, err := s.store.HasLabelMapping(ctx, tx, m)
+			if err != nil {
+				errs = errs.Append(err)
+				continue
+			}
+			if has {
+				continue
+			}
+
+			if err := s.createLabelMappingTx(ctx, tx, m); err != nil {
+				errs = errs.Append(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errs.ErrOrNil()
+}
+
+// ReplaceResourceLabels sets the resource's labels to exactly labelIDs,
+// diffing against the current mappings and applying only the delta
+// within a single KV transaction. Each added label evicts any other label
+// of its exclusive scope (see createLabelMappingTx), so where labelIDs
+// hold several labels of one scope, only one of them stays mapped.
+func (s *Service) ReplaceResourceLabels(ctx context.Context, resourceID influxdb.ID, resourceType influxdb.ResourceType, labelIDs []influxdb.ID) error {
+	var errs influxdb.MultiError
+
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		existing := []*influxdb.Label{}
+		if err := s.store.FindResourceLabels(ctx, tx, influxdb.LabelMappingFilter{ResourceID: resourceID, ResourceType: resourceType}, &existing); err != nil {
+			return err
+		}
+
+		currentIDs := make([]influxdb.ID, len(existing))
+		namesByID := make(map[influxdb.ID]string, len(existing))
+		for i, l := range existing {
+			currentIDs[i] = l.ID
+			namesByID[l.ID] = l.Name
+		}
+
+		toAdd, toRemove := diffLabelIDs(currentIDs, labelIDs)
+
+		for _, id := range toRemove {
+			m := &influxdb.LabelMapping{LabelID: id, ResourceID: resourceID, ResourceType: resourceType}
+			if err := s.store.DeleteLabelMapping(ctx, tx, m); err != nil {
+				errs = errs.Append(err)
+				continue
+			}
+			if err := s.store.deleteLabelMappingIndex(ctx, tx, m); err != nil {
+				errs = errs.Append(err)
+			}
+			if err := s.store.decrementLabelUsage(ctx, tx, id, resourceType); err != nil {
+				errs = errs.Append(err)
+			}
+			if err := recordLabelEvent(ctx, tx, s.store, LabelEvent{
+				ResourceID:   resourceID,
+				ResourceType: resourceType,
+				LabelID:      id,
+				LabelName:    namesByID[id],
+				Type:         LabelEventMappingDeleted,
+				Time:         time.Now().UTC(),
+			}); err != nil {
+				errs = errs.Append(err)
+			}
+		}
+
+		for _, id := range toAdd {
+			if err := s.createLabelMappingTx(ctx, tx, &influxdb.LabelMapping{LabelID: id, ResourceID: resourceID, ResourceType: resourceType}); err != nil {
+				errs = errs.Append(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errs.ErrOrNil()
+}
+
+// diffLabelIDs compares the current and desired label ID sets for a
+// resource and returns the mappings that must be created (toAdd) and
+// removed (toRemove) to reconcile current into desired.
+func diffLabelIDs(currentIDs, desiredIDs []influxdb.ID) (toAdd, toRemove []influxdb.ID) {
+	current := make(map[influxdb.ID]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		current[id] = true
+	}
+
+	desired := make(map[influxdb.ID]bool, len(desiredIDs))
+	for _, id := range desiredIDs {
+		desired[id] = true
+	}
+
+	for _, id := range currentIDs {
+		if !desired[id] {
+			toRemove = append(toRemove, id)
+		}
+	}
+
+	for _, id := range desiredIDs {
+		if !current[id] {
+			toAdd = append(toAdd, id)
+		}
+	}
+
+	return toAdd, toRemove
+}
+
+// ClearResourceLabels removes every label mapping for a resource within a
+// single KV transaction, aggregating per-mapping failures rather than
+// aborting on the first.
+func (s *Service) ClearResourceLabels(ctx context.Context, resourceID influxdb.ID, resourceType influxdb.ResourceType) error {
+	var errs influxdb.MultiError
+
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		existing := []*influxdb.Label{}
+		if err := s.store.FindResourceLabels(ctx, tx, influxdb.LabelMappingFilter{ResourceID: resourceID, ResourceType: resourceType}, &existing); err != nil {
+			return err
+		}
+
+		for _, l := range existing {
+			m := &influxdb.LabelMapping{LabelID: l.ID, ResourceID: resourceID, ResourceType: resourceType}
+			if err := s.store.DeleteLabelMapping(ctx, tx, m); err != nil {
+				errs = errs.Append(err)
+				continue
+			}
+			if err := s.store.deleteLabelMappingIndex(ctx, tx, m); err != nil {
+				errs = errs.Append(err)
+			}
+			if err := s.store.decrementLabelUsage(ctx, tx, l.ID, resourceType); err != nil {
+				errs = errs.Append(err)
+			}
+			if err := recordLabelEvent(ctx, tx, s.store, LabelEvent{
+				ResourceID:   resourceID,
+				ResourceType: resourceType,
+				LabelID:      l.ID,
+				LabelName:    l.Name,
+				Type:         LabelEventMappingDeleted,
+				Time:         time.Now().UTC(),
+			}); err != nil {
+				errs = errs.Append(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errs.ErrOrNil()
+}
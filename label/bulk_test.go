@@ -0,0 +1,218 @@
+package label
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// newTestService returns a Service backed by a fresh in-memory KV store, for
+// tests that need to exercise real transactions rather than pure helpers.
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	kvSvc := kv.NewService(zaptest.NewLogger(t), store)
+	if err := kvSvc.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() = %v", err)
+	}
+
+	return NewService(NewStore(store), kvSvc).(*Service)
+}
+
+func mustCreateLabel(t *testing.T, s *Service, orgID influxdb.ID, name string) *influxdb.Label {
+	t.Helper()
+
+	l := &influxdb.Label{OrgID: orgID, Name: name}
+	if err := s.CreateLabel(context.Background(), l); err != nil {
+		t.Fatalf("CreateLabel(%q) = %v", name, err)
+	}
+	return l
+}
+
+func TestAddResourceLabels(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	orgID := influxdb.ID(1)
+	a := mustCreateLabel(t, s, orgID, "a")
+	b := mustCreateLabel(t, s, orgID, "b")
+	resource := influxdb.ID(100)
+
+	if err := s.AddResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{a.ID, b.ID}); err != nil {
+		t.Fatalf("AddResourceLabels() = %v", err)
+	}
+
+	labels, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: resource, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		t.Fatalf("FindResourceLabels() = %v", err)
+	}
+	if len(labels) != 2 {
+		t.Fatalf("len(labels) = %d, want 2", len(labels))
+	}
+
+	// Adding the same label again is a no-op, not an error.
+	if err := s.AddResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{a.ID}); err != nil {
+		t.Fatalf("AddResourceLabels() on existing mapping = %v", err)
+	}
+
+	// A nonexistent label ID is reported as an error.
+	missing := influxdb.ID(999999)
+	err = s.AddResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{missing})
+	if err == nil {
+		t.Fatal("AddResourceLabels() with a nonexistent label = nil, want error")
+	}
+}
+
+func TestReplaceResourceLabels(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	orgID := influxdb.ID(1)
+	a := mustCreateLabel(t, s, orgID, "a")
+	b := mustCreateLabel(t, s, orgID, "b")
+	c := mustCreateLabel(t, s, orgID, "c")
+	resource := influxdb.ID(100)
+
+	if err := s.AddResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{a.ID, b.ID}); err != nil {
+		t.Fatalf("AddResourceLabels() = %v", err)
+	}
+
+	if err := s.ReplaceResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{b.ID, c.ID}); err != nil {
+		t.Fatalf("ReplaceResourceLabels() = %v", err)
+	}
+
+	labels, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: resource, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		t.Fatalf("FindResourceLabels() = %v", err)
+	}
+
+	got := map[influxdb.ID]bool{}
+	for _, l := range labels {
+		got[l.ID] = true
+	}
+	if len(got) != 2 || !got[b.ID] || !got[c.ID] {
+		t.Fatalf("labels after replace = %v, want {%v, %v}", got, b.ID, c.ID)
+	}
+}
+
+func TestReplaceResourceLabelsExclusiveScope(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	orgID := influxdb.ID(1)
+	high := mustCreateLabel(t, s, orgID, "priority/high")
+	low := mustCreateLabel(t, s, orgID, "priority/low")
+	resource := influxdb.ID(100)
+
+	if err := s.ReplaceResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{high.ID}); err != nil {
+		t.Fatalf("ReplaceResourceLabels() = %v", err)
+	}
+	if err := s.ReplaceResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{low.ID}); err != nil {
+		t.Fatalf("ReplaceResourceLabels() = %v", err)
+	}
+
+	labels, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: resource, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		t.Fatalf("FindResourceLabels() = %v", err)
+	}
+	if len(labels) != 1 || labels[0].ID != low.ID {
+		t.Fatalf("labels = %v, want only %v", labels, low.ID)
+	}
+}
+
+func TestClearResourceLabels(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	orgID := influxdb.ID(1)
+	a := mustCreateLabel(t, s, orgID, "a")
+	b := mustCreateLabel(t, s, orgID, "b")
+	resource := influxdb.ID(100)
+
+	if err := s.AddResourceLabels(ctx, resource, influxdb.BucketsResourceType, []influxdb.ID{a.ID, b.ID}); err != nil {
+		t.Fatalf("AddResourceLabels() = %v", err)
+	}
+
+	if err := s.ClearResourceLabels(ctx, resource, influxdb.BucketsResourceType); err != nil {
+		t.Fatalf("ClearResourceLabels() = %v", err)
+	}
+
+	labels, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: resource, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		t.Fatalf("FindResourceLabels() = %v", err)
+	}
+	if len(labels) != 0 {
+		t.Fatalf("labels after clear = %v, want none", labels)
+	}
+}
+
+func TestDiffLabelIDs(t *testing.T) {
+	id := func(i int) influxdb.ID { return influxdb.ID(i) }
+
+	tests := []struct {
+		name         string
+		current      []influxdb.ID
+		desired      []influxdb.ID
+		wantToAdd    []influxdb.ID
+		wantToRemove []influxdb.ID
+	}{
+		{
+			name:         "no change",
+			current:      []influxdb.ID{id(1), id(2)},
+			desired:      []influxdb.ID{id(1), id(2)},
+			wantToAdd:    nil,
+			wantToRemove: nil,
+		},
+		{
+			name:         "add only",
+			current:      nil,
+			desired:      []influxdb.ID{id(1), id(2)},
+			wantToAdd:    []influxdb.ID{id(1), id(2)},
+			wantToRemove: nil,
+		},
+		{
+			name:         "remove only",
+			current:      []influxdb.ID{id(1), id(2)},
+			desired:      nil,
+			wantToAdd:    nil,
+			wantToRemove: []influxdb.ID{id(1), id(2)},
+		},
+		{
+			name:         "add and remove",
+			current:      []influxdb.ID{id(1), id(2)},
+			desired:      []influxdb.ID{id(2), id(3)},
+			wantToAdd:    []influxdb.ID{id(3)},
+			wantToRemove: []influxdb.ID{id(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAdd, gotRemove := diffLabelIDs(tt.current, tt.desired)
+			if !idSlicesEqual(gotAdd, tt.wantToAdd) {
+				t.Errorf("toAdd = %v, want %v", gotAdd, tt.wantToAdd)
+			}
+			if !idSlicesEqual(gotRemove, tt.wantToRemove) {
+				t.Errorf("toRemove = %v, want %v", gotRemove, tt.wantToRemove)
+			}
+		})
+	}
+}
+
+func idSlicesEqual(a, b []influxdb.ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
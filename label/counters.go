@@ -0,0 +1,106 @@
+package label
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// HasLabel reports whether labelID is mapped to the given resource. Like
+// the existence check in createLabelMappingTx, this performs a direct
+// point lookup on the mapping index (HasLabelMapping) rather than listing
+// every label on the resource and scanning for a match.
+func (s *Service) HasLabel(ctx context.Context, resourceID influxdb.ID, resourceType influxdb.ResourceType, labelID influxdb.ID) (bool, error) {
+	var has bool
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		ok, err := s.store.HasLabelMapping(ctx, tx, &influxdb.LabelMapping{
+			LabelID:      labelID,
+			ResourceID:   resourceID,
+			ResourceType: resourceType,
+		})
+		if err != nil {
+			return err
+		}
+		has = ok
+		return nil
+	})
+	return has, err
+}
+
+// incrementLabelUsage bumps a label's usage counters when a mapping to it
+// is created.
+func (s *Store) incrementLabelUsage(ctx context.Context, tx kv.Tx, labelID influxdb.ID, resourceType influxdb.ResourceType) error {
+	l, err := s.GetLabel(ctx, tx, labelID)
+	if err != nil {
+		return err
+	}
+
+	l.NumResources++
+	if l.NumResourcesByType == nil {
+		l.NumResourcesByType = map[influxdb.ResourceType]int{}
+	}
+	l.NumResourcesByType[resourceType]++
+
+	return s.UpdateLabel(ctx, tx, l)
+}
+
+// decrementLabelUsage mirrors incrementLabelUsage for DeleteLabelMapping.
+// A label that no longer exists is not an error here: deleting the last
+// mapping to a label that was deleted out from under it is a no-op.
+func (s *Store) decrementLabelUsage(ctx context.Context, tx kv.Tx, labelID influxdb.ID, resourceType influxdb.ResourceType) error {
+	l, err := s.GetLabel(ctx, tx, labelID)
+	if kv.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if l.NumResources > 0 {
+		l.NumResources--
+	}
+	if l.NumResourcesByType[resourceType] > 0 {
+		l.NumResourcesByType[resourceType]--
+	}
+
+	return s.UpdateLabel(ctx, tx, l)
+}
+
+// PopulateLabelUsageCounters walks every label mapping once and
+// recalculates each label's NumResources / NumResourcesByType counters.
+// It is intended to run as a one-time startup migration for KV stores
+// created before usage counters existed.
+func (s *Store) PopulateLabelUsageCounters(ctx context.Context, tx kv.Tx) error {
+	labels, err := s.ListLabels(ctx, tx, influxdb.LabelFilter{})
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[influxdb.ID]int, len(labels))
+	countsByType := make(map[influxdb.ID]map[influxdb.ResourceType]int, len(labels))
+
+	err = s.forEachLabelMapping(ctx, tx, func(m *influxdb.LabelMapping) error {
+		counts[m.LabelID]++
+		byType, ok := countsByType[m.LabelID]
+		if !ok {
+			byType = map[influxdb.ResourceType]int{}
+			countsByType[m.LabelID] = byType
+		}
+		byType[m.ResourceType]++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, l := range labels {
+		l.NumResources = counts[l.ID]
+		l.NumResourcesByType = countsByType[l.ID]
+		if err := s.UpdateLabel(ctx, tx, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package label
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// labelScope returns the exclusive scope for a label name and whether the
+// name declares one at all. Scopes are inferred from a "/"-separated
+// prefix, e.g. "priority/high" and "priority/low" both belong to the
+// "priority" scope and are mutually exclusive on a given resource.
+func labelScope(name string) (scope string, ok bool) {
+	i := strings.LastIndex(name, "/")
+	if i <= 0 {
+		return "", false
+	}
+	return name[:i], true
+}
+
+// createLabelMappingTx creates m against an already-open transaction,
+// evicting any existing mapping in the same exclusive scope (see
+// labelScope) first.
+func (s *Service) createLabelMappingTx(ctx context.Context, tx kv.Tx, m *influxdb.LabelMapping) error {
+	lbl, err := s.store.GetLabel(ctx, tx, m.LabelID)
+	if err != nil {
+		return err // todo (al) not found error
+	}
+
+	has, err := s.store.HasLabelMapping(ctx, tx, m)
+	if err != nil {
+		return err
+	}
+	if has {
+		return influxdb.ErrLabelExistsOnResource
+	}
+
+	scope, scoped := labelScope(lbl.Name)
+	if scoped {
+		// Only a scoped label needs the full resource label list, to find
+		// any existing mapping in the same scope to evict.
+		ls := []*influxdb.Label{}
+		if err := s.store.FindResourceLabels(ctx, tx, influxdb.LabelMappingFilter{ResourceID: m.ResourceID, ResourceType: m.ResourceType}, &ls); err != nil {
+			return err
+		}
+
+		for _, existing := range ls {
+			existingScope, ok := labelScope(existing.Name)
+			if !ok || existingScope != scope {
+				continue
+			}
+
+			conflict := &influxdb.LabelMapping{LabelID: existing.ID, ResourceID: m.ResourceID, ResourceType: m.ResourceType}
+			if err := s.store.DeleteLabelMapping(ctx, tx, conflict); err != nil {
+				return err
+			}
+			if err := s.store.deleteLabelMappingIndex(ctx, tx, conflict); err != nil {
+				return err
+			}
+			if err := s.store.decrementLabelUsage(ctx, tx, existing.ID, m.ResourceType); err != nil {
+				return err
+			}
+			if err := recordLabelEvent(ctx, tx, s.store, LabelEvent{
+				ResourceID:   m.ResourceID,
+				ResourceType: m.ResourceType,
+				LabelID:      existing.ID,
+				LabelName:    existing.Name,
+				Type:         LabelEventMappingDeleted,
+				Time:         time.Now().UTC(),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.store.CreateLabelMapping(ctx, tx, m); err != nil {
+		return err
+	}
+	if err := s.store.putLabelMappingIndex(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := s.store.incrementLabelUsage(ctx, tx, m.LabelID, m.ResourceType); err != nil {
+		return err
+	}
+
+	return recordLabelEvent(ctx, tx, s.store, LabelEvent{
+		ResourceID:   m.ResourceID,
+		ResourceType: m.ResourceType,
+		LabelID:      m.LabelID,
+		LabelName:    lbl.Name,
+		Type:         LabelEventMappingCreated,
+		Time:         time.Now().UTC(),
+	})
+}
+
+// RemoveDuplicateExclusiveLabels reconciles mappings created before
+// exclusive label scopes existed. For each scope with more than one label
+// mapped to the resource, all but the first mapping encountered are
+// removed so the resource ends up with at most one label per scope.
+func (s *Store) RemoveDuplicateExclusiveLabels(ctx context.Context, tx kv.Tx, resourceID influxdb.ID, resourceType influxdb.ResourceType) error {
+	ls := []*influxdb.Label{}
+	if err := s.FindResourceLabels(ctx, tx, influxdb.LabelMappingFilter{ResourceID: resourceID, ResourceType: resourceType}, &ls); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, l := range ls {
+		scope, ok := labelScope(l.Name)
+		if !ok {
+			continue
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			continue
+		}
+
+		m := &influxdb.LabelMapping{LabelID: l.ID, ResourceID: resourceID, ResourceType: resourceType}
+		if err := s.DeleteLabelMapping(ctx, tx, m); err != nil {
+			return err
+		}
+		if err := s.deleteLabelMappingIndex(ctx, tx, m); err != nil {
+			return err
+		}
+		if err := s.decrementLabelUsage(ctx, tx, l.ID, resourceType); err != nil {
+			return err
+		}
+		if err := recordLabelEvent(ctx, tx, s, LabelEvent{
+			ResourceID:   resourceID,
+			ResourceType: resourceType,
+			LabelID:      l.ID,
+			LabelName:    l.Name,
+			Type:         LabelEventMappingDeleted,
+			Time:         time.Now().UTC(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
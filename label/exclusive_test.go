@@ -0,0 +1,52 @@
+package label
+
+import "testing"
+
+func TestLabelScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		label     string
+		wantScope string
+		wantOK    bool
+	}{
+		{
+			name:      "scoped label",
+			label:     "priority/high",
+			wantScope: "priority",
+			wantOK:    true,
+		},
+		{
+			name:      "nested scope uses last separator",
+			label:     "team/infra/owner",
+			wantScope: "team/infra",
+			wantOK:    true,
+		},
+		{
+			name:      "unscoped label",
+			label:     "bug",
+			wantScope: "",
+			wantOK:    false,
+		},
+		{
+			name:      "leading slash is not a scope",
+			label:     "/bug",
+			wantScope: "",
+			wantOK:    false,
+		},
+		{
+			name:      "empty name",
+			label:     "",
+			wantScope: "",
+			wantOK:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotScope, gotOK := labelScope(tt.label)
+			if gotScope != tt.wantScope || gotOK != tt.wantOK {
+				t.Errorf("labelScope(%q) = (%q, %v), want (%q, %v)", tt.label, gotScope, gotOK, tt.wantScope, tt.wantOK)
+			}
+		})
+	}
+}
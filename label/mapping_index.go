@@ -0,0 +1,129 @@
+package label
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// labelMappingIndexBucket stores every label mapping keyed by
+// labelID || resourceID || resourceType, so a mapping can be looked up
+// directly (HasLabelMapping), listed by label (findLabelMappings), or
+// walked in full (forEachLabelMapping) without scanning a resource's
+// label list.
+var labelMappingIndexBucket = []byte("labelmappingindex")
+
+// labelMappingIndexKey encodes m's index key. Leading with the label ID
+// lets findLabelMappings do a prefix scan for every resource a label is
+// mapped to.
+func labelMappingIndexKey(m *influxdb.LabelMapping) ([]byte, error) {
+	encodedLabelID, err := m.LabelID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	encodedResourceID, err := m.ResourceID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 0, len(encodedLabelID)+len(encodedResourceID)+len(m.ResourceType))
+	key = append(key, encodedLabelID...)
+	key = append(key, encodedResourceID...)
+	key = append(key, []byte(m.ResourceType)...)
+	return key, nil
+}
+
+// putLabelMappingIndex records m in the mapping index. Callers creating a
+// mapping must call this alongside s.store.CreateLabelMapping to keep the
+// index consistent.
+func (s *Store) putLabelMappingIndex(ctx context.Context, tx kv.Tx, m *influxdb.LabelMapping) error {
+	b, err := tx.Bucket(labelMappingIndexBucket)
+	if err != nil {
+		return kv.UnexpectedIndexError(err)
+	}
+
+	key, err := labelMappingIndexKey(m)
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(key, val)
+}
+
+// deleteLabelMappingIndex removes m from the mapping index. Callers
+// deleting a mapping must call this alongside s.store.DeleteLabelMapping
+// to keep the index consistent.
+func (s *Store) deleteLabelMappingIndex(ctx context.Context, tx kv.Tx, m *influxdb.LabelMapping) error {
+	b, err := tx.Bucket(labelMappingIndexBucket)
+	if err != nil {
+		return kv.UnexpectedIndexError(err)
+	}
+
+	key, err := labelMappingIndexKey(m)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(key)
+}
+
+// HasLabelMapping reports whether m already exists via a direct point
+// lookup on the mapping index, rather than listing every label on the
+// resource and scanning for a match.
+func (s *Store) HasLabelMapping(ctx context.Context, tx kv.Tx, m *influxdb.LabelMapping) (bool, error) {
+	b, err := tx.Bucket(labelMappingIndexBucket)
+	if err != nil {
+		return false, kv.UnexpectedIndexError(err)
+	}
+
+	key, err := labelMappingIndexKey(m)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = b.Get(key)
+	if kv.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// forEachLabelMapping walks every mapping in the index, calling fn for
+// each. It is used by PopulateLabelUsageCounters to recompute usage
+// counts from scratch.
+func (s *Store) forEachLabelMapping(ctx context.Context, tx kv.Tx, fn func(*influxdb.LabelMapping) error) error {
+	b, err := tx.Bucket(labelMappingIndexBucket)
+	if err != nil {
+		return kv.UnexpectedIndexError(err)
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var m influxdb.LabelMapping
+		if err := json.Unmarshal(v, &m); err != nil {
+			return err
+		}
+		if err := fn(&m); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
@@ -0,0 +1,23 @@
+package label
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/kv/migration"
+)
+
+// MigrationPopulateUsageCounters is a one-time startup migration for KV
+// stores created before label usage counters existed.
+//
+// todo (al) this still needs to be added to the Migrations list in
+// kv/migration/all/all.go before it will actually run at startup.
+var MigrationPopulateUsageCounters = migration.UpOnlyMigration(
+	"populate label usage counters",
+	func(ctx context.Context, store kv.SchemaStore) error {
+		s := NewStore(store)
+		return s.Update(ctx, func(tx kv.Tx) error {
+			return s.PopulateLabelUsageCounters(ctx, tx)
+		})
+	},
+)
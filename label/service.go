@@ -2,7 +2,9 @@ package label
 
 import (
 	"context"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kv"
@@ -31,23 +33,24 @@ func (s *Service) CreateLabel(ctx context.Context, l *influxdb.Label) error {
 
 	l.Name = strings.TrimSpace(l.Name)
 
-	err := s.store.Update(ctx, func(tx kv.Tx) error {
-		if err := uniqueLabelName(ctx, tx, l); err != nil {
-			return err
-		}
-
-		if err := s.store.CreateLabel(ctx, tx, l); err != nil {
-			return err
-		}
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		return s.createLabelTx(ctx, tx, l)
+	})
+}
 
-		if err := s.kvSvc.CreateUserResourceMappingForOrg(ctx, tx, l.OrgID, l.ID, influxdb.LabelsResourceType); err != nil {
-			return err
-		}
+// createLabelTx creates l against an already-open transaction: unique-name
+// check, persisting the label, and creating its URM so the label has an
+// ownership record.
+func (s *Service) createLabelTx(ctx context.Context, tx kv.Tx, l *influxdb.Label) error {
+	if err := uniqueLabelName(ctx, tx, l); err != nil {
+		return err
+	}
 
-		return nil
-	})
+	if err := s.store.CreateLabel(ctx, tx, l); err != nil {
+		return err
+	}
 
-	return err
+	return s.kvSvc.CreateUserResourceMappingForOrg(ctx, tx, l.OrgID, l.ID, influxdb.LabelsResourceType)
 }
 
 // FindLabelByID finds a label by its ID
@@ -88,6 +91,14 @@ func (s *Service) FindLabels(ctx context.Context, filter influxdb.LabelFilter, o
 		return nil, err
 	}
 
+	for _, o := range opt {
+		if o.SortBy != "usage" {
+			continue
+		}
+		sort.Slice(ls, func(i, j int) bool { return ls[i].NumResources > ls[j].NumResources })
+		break
+	}
+
 	return ls, nil
 }
 
@@ -183,6 +194,30 @@ func (s *Service) UpdateLabel(ctx context.Context, id influxdb.ID, upd influxdb.
 				Err: e,
 			}
 		}
+
+		mapped, e := s.store.FindMappedResources(ctx, tx, label.ID)
+		if e != nil {
+			return &influxdb.Error{
+				Err: e,
+			}
+		}
+
+		for _, m := range mapped {
+			evt := LabelEvent{
+				ResourceID:   m.ResourceID,
+				ResourceType: m.ResourceType,
+				LabelID:      label.ID,
+				LabelName:    label.Name,
+				Type:         LabelEventLabelUpdated,
+				Time:         time.Now().UTC(),
+			}
+			if e := recordLabelEvent(ctx, tx, s.store, evt); e != nil {
+				return &influxdb.Error{
+					Err: e,
+				}
+			}
+		}
+
 		return nil
 	})
 
@@ -204,38 +239,48 @@ func (s *Service) DeleteLabel(ctx context.Context, id influxdb.ID) error {
 
 //******* Label Mappings *******//
 
-// CreateLabelMapping creates a new mapping between a resource and a label.
+// CreateLabelMapping creates a new mapping between a resource and a label,
+// evicting any conflicting exclusive-scope mapping first (see
+// createLabelMappingTx).
 func (s *Service) CreateLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
-	err := s.store.View(ctx, func(tx kv.Tx) error {
-		if _, err := s.store.GetLabel(ctx, tx, m.LabelID); err != nil {
-			return err
-		}
-		ls := []*influxdb.Label{}
-		err := s.store.FindResourceLabels(ctx, tx, influxdb.LabelMappingFilter{ResourceID: m.ResourceID, ResourceType: m.ResourceType}, &ls)
-		if err != nil {
-			return err
-		}
-		for i := 0; i < len(ls); i++ {
-			if ls[i].ID == m.LabelID {
-				return influxdb.ErrLabelExistsOnResource
-			}
-		}
-
-		return nil
-	})
-	if err != nil {
-		return err // todo (al) not found error
-	}
-
 	return s.store.Update(ctx, func(tx kv.Tx) error {
-		return s.store.CreateLabelMapping(ctx, tx, m)
+		return s.createLabelMappingTx(ctx, tx, m)
 	})
 }
 
 // DeleteLabelMapping deletes a label mapping.
 func (s *Service) DeleteLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
 	err := s.store.Update(ctx, func(tx kv.Tx) error {
-		return s.store.DeleteLabelMapping(ctx, tx, m)
+		// The label backing this mapping may already be gone (DeleteLabel
+		// does not clean up its mappings), so a missing label must not
+		// block cleanup of an orphaned mapping. Fall back to a
+		// placeholder name for the audit snapshot in that case.
+		labelName := "unknown"
+		if lbl, err := s.store.GetLabel(ctx, tx, m.LabelID); err == nil {
+			labelName = lbl.Name
+		} else if !kv.IsNotFound(err) {
+			return err
+		}
+
+		if err := s.store.DeleteLabelMapping(ctx, tx, m); err != nil {
+			return err
+		}
+		if err := s.store.deleteLabelMappingIndex(ctx, tx, m); err != nil {
+			return err
+		}
+
+		if err := s.store.decrementLabelUsage(ctx, tx, m.LabelID, m.ResourceType); err != nil {
+			return err
+		}
+
+		return recordLabelEvent(ctx, tx, s.store, LabelEvent{
+			ResourceID:   m.ResourceID,
+			ResourceType: m.ResourceType,
+			LabelID:      m.LabelID,
+			LabelName:    labelName,
+			Type:         LabelEventMappingDeleted,
+			Time:         time.Now().UTC(),
+		})
 	})
 	if err != nil {
 		return &influxdb.Error{
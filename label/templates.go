@@ -0,0 +1,184 @@
+package label
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// CopyLabelOptions controls how CopyLabelsToOrg materializes template
+// labels into a destination organization.
+type CopyLabelOptions struct {
+	// SkipExisting leaves dstOrgID's label alone when one with the same
+	// name already exists there. It is currently the only supported
+	// conflict strategy; the field exists so callers can be explicit
+	// about intent as more strategies are added.
+	SkipExisting bool
+}
+
+// CopyLabelsToOrg materializes every label marked Template on srcOrgID
+// into dstOrgID as new labels, preserving name, color, and properties.
+// A destination label that already shares a source label's name is left
+// untouched rather than duplicated.
+func (s *Service) CopyLabelsToOrg(ctx context.Context, srcOrgID, dstOrgID influxdb.ID, opts CopyLabelOptions) ([]*influxdb.Label, error) {
+	var created []*influxdb.Label
+
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		var txCreated []*influxdb.Label
+		srcLabels, err := s.store.ListLabels(ctx, tx, influxdb.LabelFilter{OrgID: &srcOrgID})
+		if err != nil {
+			return err
+		}
+
+		dstLabels, err := s.store.ListLabels(ctx, tx, influxdb.LabelFilter{OrgID: &dstOrgID})
+		if err != nil {
+			return err
+		}
+
+		existingNames := make(map[string]bool, len(dstLabels))
+		for _, l := range dstLabels {
+			existingNames[l.Name] = true
+		}
+
+		for _, src := range srcLabels {
+			if !src.Template || existingNames[src.Name] {
+				continue
+			}
+
+			dst := &influxdb.Label{
+				OrgID:      dstOrgID,
+				Name:       src.Name,
+				Color:      src.Color,
+				Properties: copyLabelProperties(src.Properties),
+			}
+
+			if err := dst.Validate(); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Err:  err,
+				}
+			}
+
+			if err := s.createLabelTx(ctx, tx, dst); err != nil {
+				return err
+			}
+
+			txCreated = append(txCreated, dst)
+		}
+
+		created = txCreated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func copyLabelProperties(props map[string]string) map[string]string {
+	if props == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(props))
+	for k, v := range props {
+		out[k] = v
+	}
+	return out
+}
+
+// LabelSpec declaratively describes a label an operator wants to exist in
+// an organization, used by ImportLabels to reconcile a standard label
+// taxonomy across many organizations.
+type LabelSpec struct {
+	Name        string
+	Color       string
+	Description string
+	Properties  map[string]string
+}
+
+// ImportLabels reconciles orgID's labels against specs: a spec with no
+// matching label by name is created, a spec whose fields differ from the
+// existing label updates it, and existing labels with no matching spec
+// are left alone. Per-label failures are aggregated into an
+// influxdb.MultiError rather than aborting the whole import.
+func (s *Service) ImportLabels(ctx context.Context, orgID influxdb.ID, specs []LabelSpec) error {
+	var errs influxdb.MultiError
+
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		existing, err := s.store.ListLabels(ctx, tx, influxdb.LabelFilter{OrgID: &orgID})
+		if err != nil {
+			return err
+		}
+
+		byName := make(map[string]*influxdb.Label, len(existing))
+		for _, l := range existing {
+			byName[l.Name] = l
+		}
+
+		for _, spec := range specs {
+			l, ok := byName[spec.Name]
+			if !ok {
+				newLabel := &influxdb.Label{
+					OrgID:       orgID,
+					Name:        spec.Name,
+					Color:       spec.Color,
+					Description: spec.Description,
+					Properties:  spec.Properties,
+				}
+
+				if err := newLabel.Validate(); err != nil {
+					errs = errs.Append(err)
+					continue
+				}
+				if err := s.createLabelTx(ctx, tx, newLabel); err != nil {
+					errs = errs.Append(err)
+				}
+				continue
+			}
+
+			if labelSpecMatches(l, spec) {
+				continue
+			}
+
+			l.Color = spec.Color
+			l.Description = spec.Description
+			l.Properties = spec.Properties
+			if err := l.Validate(); err != nil {
+				errs = errs.Append(err)
+				continue
+			}
+			if err := s.store.UpdateLabel(ctx, tx, l); err != nil {
+				errs = errs.Append(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errs.ErrOrNil()
+}
+
+func labelSpecMatches(l *influxdb.Label, spec LabelSpec) bool {
+	if l.Color != spec.Color || l.Description != spec.Description {
+		return false
+	}
+	return propertiesEqual(l.Properties, spec.Properties)
+}
+
+func propertiesEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
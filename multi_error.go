@@ -0,0 +1,35 @@
+package influxdb
+
+import "strings"
+
+// MultiError accumulates errors from a batch of independent operations,
+// e.g. applying several labels to a resource, so a failure on one item
+// doesn't prevent the rest from being attempted.
+type MultiError []error
+
+// Error joins the underlying errors' messages.
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Append adds err to m if it is non-nil and returns the result.
+func (m MultiError) Append(err error) MultiError {
+	if err == nil {
+		return m
+	}
+	return append(m, err)
+}
+
+// ErrOrNil returns m as an error if it contains any errors, or nil
+// otherwise, so callers can build up a MultiError across a loop and
+// return it unconditionally.
+func (m MultiError) ErrOrNil() error {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
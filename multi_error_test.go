@@ -0,0 +1,42 @@
+package influxdb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestMultiError(t *testing.T) {
+	t.Run("ErrOrNil returns nil when empty", func(t *testing.T) {
+		var errs influxdb.MultiError
+		if got := errs.ErrOrNil(); got != nil {
+			t.Errorf("ErrOrNil() = %v, want nil", got)
+		}
+	})
+
+	t.Run("Append ignores nil errors", func(t *testing.T) {
+		var errs influxdb.MultiError
+		errs = errs.Append(nil)
+		if got := errs.ErrOrNil(); got != nil {
+			t.Errorf("ErrOrNil() = %v, want nil", got)
+		}
+	})
+
+	t.Run("Append accumulates and ErrOrNil returns the aggregate", func(t *testing.T) {
+		var errs influxdb.MultiError
+		errs = errs.Append(errors.New("first"))
+		errs = errs.Append(nil)
+		errs = errs.Append(errors.New("second"))
+
+		got := errs.ErrOrNil()
+		if got == nil {
+			t.Fatal("ErrOrNil() = nil, want non-nil")
+		}
+
+		want := "first; second"
+		if got.Error() != want {
+			t.Errorf("Error() = %q, want %q", got.Error(), want)
+		}
+	})
+}